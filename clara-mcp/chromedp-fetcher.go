@@ -5,23 +5,78 @@ import (
 	"fmt"
 	"log"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
 	"github.com/chromedp/chromedp"
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
 )
 
+// ScreenshotOptions configures full-page or viewport screenshot capture
+type ScreenshotOptions struct {
+	FullPage bool   // capture the entire scrollable page, not just the viewport
+	Format   string // "png" or "jpeg"; defaults to "png"
+	Quality  int    // jpeg quality 0-100, ignored for png
+	Width    int64  // viewport width, defaults to 1280
+	Height   int64  // viewport height, defaults to 800
+
+	// WaitSelector, when set, is awaited via chromedp.WaitVisible before
+	// capture instead of the default network-idle wait.
+	WaitSelector string
+}
+
+// PDFOptions configures page.PrintToPDF export
+type PDFOptions struct {
+	Landscape       bool
+	PrintBackground bool
+	PaperWidth      float64 // inches, defaults to 8.5
+	PaperHeight     float64 // inches, defaults to 11
+
+	// WaitSelector, when set, is awaited via chromedp.WaitVisible before
+	// export instead of the default network-idle wait.
+	WaitSelector string
+}
+
+// defaultMaxConcurrentTabs bounds how many tabs FetchContent and friends will
+// keep open at once when the caller doesn't configure a different limit.
+const defaultMaxConcurrentTabs = 5
+
 // ChromeDPManager handles lightweight browser automation with chromedp
 type ChromeDPManager struct {
 	ctx    context.Context
 	cancel context.CancelFunc
-	mu     sync.RWMutex
+
+	// tabSem bounds the number of concurrently open per-fetch tabs.
+	tabSem chan struct{}
+
+	// remote is true when ctx/cancel came from NewRemoteAllocator, in which
+	// case Cleanup must not cancel them: cancelling the browser context that
+	// allocated a browser is how chromedp.Cancel decides to gracefully shut
+	// the browser down (it sends Browser.Close), which would kill a shared
+	// remote Chrome instead of merely detaching from it.
+	remote bool
+}
+
+// Option configures a ChromeDPManager at construction time
+type Option func(*ChromeDPManager)
+
+// WithMaxConcurrentTabs overrides the default cap on concurrently open tabs
+func WithMaxConcurrentTabs(n int) Option {
+	return func(cm *ChromeDPManager) {
+		if n > 0 {
+			cm.tabSem = make(chan struct{}, n)
+		}
+	}
 }
 
 // NewChromeDPManager creates a new chromedp manager with a persistent browser context
-func NewChromeDPManager() *ChromeDPManager {
+func NewChromeDPManager(opts ...Option) *ChromeDPManager {
 	// Create a persistent browser context
-	opts := append(chromedp.DefaultExecAllocatorOptions[:],
+	allocOpts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.Flag("headless", true),
 		chromedp.Flag("disable-gpu", true),
 		chromedp.Flag("no-sandbox", true),
@@ -30,104 +85,401 @@ func NewChromeDPManager() *ChromeDPManager {
 		chromedp.UserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"),
 	)
 
-	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), opts...)
+	allocCtx, cancel := chromedp.NewExecAllocator(context.Background(), allocOpts...)
 	ctx, _ := chromedp.NewContext(allocCtx)
 
-	return &ChromeDPManager{
+	cm := newChromeDPManager(ctx, cancel, false, opts...)
+
+	return cm
+}
+
+// NewChromeDPManagerRemote attaches to an already-running Chrome instance over
+// the DevTools protocol instead of launching a bundled one, e.g. a shared
+// browserless/chrome container or the user's own Chrome started with
+// --remote-debugging-port. The remote browser's lifetime is independent of
+// this process: each call's tab context is cancelled when that call returns,
+// and Cleanup deliberately never cancels the allocator context, since doing
+// so would make chromedp send the remote browser a graceful Browser.Close.
+func NewChromeDPManagerRemote(wsURL string, opts ...Option) *ChromeDPManager {
+	allocCtx, cancel := chromedp.NewRemoteAllocator(context.Background(), wsURL)
+	ctx, _ := chromedp.NewContext(allocCtx)
+
+	return newChromeDPManager(ctx, cancel, true, opts...)
+}
+
+// newChromeDPManager wires up the dialog listener and tab semaphore shared by
+// both the exec-allocator and remote-allocator constructors.
+func newChromeDPManager(ctx context.Context, cancel context.CancelFunc, remote bool, opts ...Option) *ChromeDPManager {
+	// Auto-accept any JS dialog (alert/confirm/prompt/beforeunload) so a page
+	// that pops one doesn't hang FetchContent forever.
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if ev, ok := ev.(*page.EventJavascriptDialogOpening); ok {
+			go func() {
+				_ = chromedp.Run(ctx, page.HandleJavaScriptDialog(true))
+			}()
+			log.Printf("ChromeDPManager: auto-accepted %s dialog: %q", ev.Type, ev.Message)
+		}
+	})
+
+	cm := &ChromeDPManager{
 		ctx:    ctx,
 		cancel: cancel,
+		tabSem: make(chan struct{}, defaultMaxConcurrentTabs),
+		remote: remote,
 	}
+
+	for _, opt := range opts {
+		opt(cm)
+	}
+
+	return cm
+}
+
+// newTab acquires a slot in the tab semaphore and derives a fresh, isolated
+// chromedp context from cm.ctx, bounded by timeout. cm.ctx itself is never
+// run, so no browser is allocated on it and the derived context does not
+// open a tab in a shared browser: its first Run allocates a browser of its
+// own through cm.ctx's allocator (a separate Chrome process for the exec
+// allocator, a separate connection for the remote one).
+// The wait for a free slot counts against the same timeout as the fetch
+// itself, so a caller-supplied timeout is honored even when every slot is
+// busy instead of blocking indefinitely for one to open up. The returned
+// cancel releases both the timeout and the tab itself, and must always be
+// called so the semaphore slot (if acquired) is freed.
+func (cm *ChromeDPManager) newTab(timeout time.Duration) (context.Context, context.CancelFunc) {
+	deadline := time.Now().Add(timeout)
+
+	select {
+	case cm.tabSem <- struct{}{}:
+	case <-time.After(time.Until(deadline)):
+		// Still hand back a chromedp context, so callers' ListenTarget calls
+		// stay valid; it is already past its deadline, so their Run fails
+		// with context.DeadlineExceeded.
+		tabCtx, tabCancel := chromedp.NewContext(cm.ctx)
+		ctx, timeoutCancel := context.WithDeadline(tabCtx, deadline)
+		return ctx, func() {
+			timeoutCancel()
+			tabCancel()
+		}
+	}
+
+	tabCtx, tabCancel := chromedp.NewContext(cm.ctx)
+	ctx, timeoutCancel := context.WithDeadline(tabCtx, deadline)
+
+	cancel := func() {
+		timeoutCancel()
+		tabCancel()
+		<-cm.tabSem
+	}
+
+	return ctx, cancel
+}
+
+// defaultQuietPeriod is how long the in-flight request counter must stay at
+// zero before the page is considered network-idle.
+const defaultQuietPeriod = 500 * time.Millisecond
+
+// FetchOptions configures a single FetchContent call
+type FetchOptions struct {
+	Timeout time.Duration
+
+	// WaitSelector, when set, is awaited via chromedp.WaitVisible before
+	// extraction so callers who know the SPA's ready marker can skip
+	// network-idle guessing entirely.
+	WaitSelector string
+
+	// QuietPeriod is how long in-flight network requests must stay at zero
+	// before the page is treated as idle. Defaults to 500ms.
+	QuietPeriod time.Duration
+}
+
+// waitPageReady returns the actions that wait for targetURL's page to finish
+// loading before it's extracted or captured: the body becoming visible, then
+// either the caller's WaitSelector or a network-idle wait. Screenshot, PDF
+// and FetchContentWithOptions all navigate through this instead of guessing
+// a fixed sleep.
+func waitPageReady(ctx context.Context, waitSelector string, quietPeriod time.Duration) []chromedp.Action {
+	actions := []chromedp.Action{chromedp.WaitVisible("body", chromedp.ByQuery)}
+	if waitSelector != "" {
+		// The caller knows the SPA's ready marker; trust it over idle detection.
+		return append(actions, chromedp.WaitVisible(waitSelector, chromedp.ByQuery))
+	}
+	return append(actions, waitNetworkIdleAction(ctx, quietPeriod))
+}
+
+// waitNetworkIdleAction installs a request-tracking listener on ctx and
+// returns an action that blocks until in-flight requests have stayed at zero
+// for quietPeriod, or the tab's own timeout fires.
+func waitNetworkIdleAction(ctx context.Context, quietPeriod time.Duration) chromedp.Action {
+	// inFlight counts requests that have started but not yet finished or
+	// failed, so we can wait for the page to go quiet instead of sleeping a
+	// fixed duration.
+	var inFlight int64
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *network.EventRequestWillBeSent:
+			// A redirect re-fires requestWillBeSent for the same request ID
+			// with RedirectResponse set, and the redirected leg never gets
+			// its own LoadingFinished/LoadingFailed. Treat it as the old leg
+			// completing and the new one starting, net zero, so inFlight
+			// doesn't leak +1 per redirect.
+			if ev.RedirectResponse == nil {
+				atomic.AddInt64(&inFlight, 1)
+			}
+		case *network.EventLoadingFinished:
+			atomic.AddInt64(&inFlight, -1)
+		case *network.EventLoadingFailed:
+			atomic.AddInt64(&inFlight, -1)
+		}
+	})
+
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		quietDeadline := time.Now().Add(quietPeriod)
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+				if atomic.LoadInt64(&inFlight) > 0 {
+					quietDeadline = time.Now().Add(quietPeriod)
+					continue
+				}
+				if time.Now().After(quietDeadline) {
+					return nil
+				}
+			}
+		}
+	})
 }
 
 // FetchContent fetches web content using chromedp with JavaScript execution
 func (cm *ChromeDPManager) FetchContent(targetURL string, timeout time.Duration) (*WebContent, error) {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
+	return cm.FetchContentWithOptions(targetURL, FetchOptions{Timeout: timeout})
+}
 
+// FetchContentWithOptions is like FetchContent but lets the caller tune how
+// page readiness is detected instead of relying on a fixed sleep.
+func (cm *ChromeDPManager) FetchContentWithOptions(targetURL string, opts FetchOptions) (*WebContent, error) {
+	timeout := opts.Timeout
 	if timeout == 0 {
 		timeout = 10 * time.Second
 	}
+	quietPeriod := opts.QuietPeriod
+	if quietPeriod == 0 {
+		quietPeriod = defaultQuietPeriod
+	}
 
-	// Create a timeout context for this specific request
-	ctx, cancel := context.WithTimeout(cm.ctx, timeout)
+	// Run this fetch in its own tab so concurrent calls don't serialize
+	// behind a single shared page.
+	ctx, cancel := cm.newTab(timeout)
 	defer cancel()
 
 	startTime := time.Now()
 
-	var title, htmlContent string
-	var description string
+	var rawHTML string
 
-	// Navigate and wait for the page to load
-	err := chromedp.Run(ctx,
+	// Track the main frame's document response so we can report the real
+	// HTTP status code, headers and MIME type instead of assuming 200.
+	// mainFrameID is read from the tab's frame tree before navigating, since
+	// the document's responseReceived arrives before its frameNavigated;
+	// page.FrameID and network.FrameID share the same underlying string
+	// representation, so a direct typed comparison is enough once it's set.
+	var mainFrameID network.FrameID
+	var statusCode int64 = 200
+	var responseHeaders network.Headers
+	var mimeType string
+	var finalURL string
+
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch ev := ev.(type) {
+		case *page.EventFrameNavigated:
+			if ev.Frame.ParentID == "" {
+				mainFrameID = network.FrameID(ev.Frame.ID)
+			}
+		case *network.EventResponseReceived:
+			if ev.Type == network.ResourceTypeDocument && mainFrameID != "" && ev.FrameID == mainFrameID {
+				statusCode = ev.Response.Status
+				responseHeaders = ev.Response.Headers
+				mimeType = ev.Response.MimeType
+				finalURL = ev.Response.URL
+			}
+		}
+	})
+
+	readyActions := append([]chromedp.Action{
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			tree, err := page.GetFrameTree().Do(ctx)
+			if err != nil {
+				return err
+			}
+			mainFrameID = network.FrameID(tree.Frame.ID)
+			return nil
+		}),
 		chromedp.Navigate(targetURL),
-		// Wait for body to be present
-		chromedp.WaitVisible("body", chromedp.ByQuery),
-		// Wait a bit for JavaScript to execute
-		chromedp.Sleep(2*time.Second),
-		// Remove script and style tags
-		chromedp.Evaluate(`
-			document.querySelectorAll('script, style, noscript, iframe').forEach(el => el.remove());
-		`, nil),
-		// Get the title
-		chromedp.Title(&title),
-		// Get meta description
-		chromedp.Evaluate(`
-			(() => {
-				const meta = document.querySelector('meta[name="description"]');
-				return meta ? meta.getAttribute('content') : '';
-			})()
-		`, &description),
-		// Extract text content from semantic elements
-		chromedp.Evaluate(`
-			(() => {
-				const selectors = [
-					'h1', 'h2', 'h3', 'h4', 'h5', 'h6',
-					'p', 'span', 'div', 'article', 'section', 'main',
-					'li', 'td', 'th', 'blockquote', 'figcaption',
-					'address', 'time', 'strong', 'b', 'em', 'i',
-					'code', 'pre', 'cite', 'mark', 'a', 'button', 'label'
-				];
-
-				const seen = new Set();
-				const content = [];
-
-				selectors.forEach(selector => {
-					document.querySelectorAll(selector).forEach(el => {
-						const text = el.textContent.trim();
-						if (text && text.length > 2 && !seen.has(text)) {
-							// Filter out CSS-like content
-							if (!text.includes('{') && !text.includes('rgba(') &&
-								!text.includes('function(') && !text.startsWith('data-')) {
-								seen.add(text);
-								content.push(text);
-							}
-						}
-					});
-				});
-
-				return content.join('\n');
-			})()
-		`, &htmlContent),
-	)
+	}, waitPageReady(ctx, opts.WaitSelector, quietPeriod)...)
+
+	// Navigate, wait for readiness, then grab the fully rendered DOM so
+	// extraction happens in Go instead of a bespoke in-page JS selector walk.
+	err := chromedp.Run(ctx, append(readyActions,
+		chromedp.OuterHTML("html", &rawHTML, chromedp.ByQuery),
+	)...)
 
 	if err != nil {
 		return nil, fmt.Errorf("chromedp navigation failed: %v", err)
 	}
 
+	extracted, err := extractContent(rawHTML)
+	if err != nil {
+		return nil, fmt.Errorf("content extraction failed: %v", err)
+	}
+
+	resultURL := finalURL
+	if resultURL == "" {
+		resultURL = targetURL
+	}
+
 	return &WebContent{
-		URL:             targetURL,
-		Title:           title,
-		Content:         htmlContent,
-		Description:     description,
-		StatusCode:      200,
+		URL:             resultURL,
+		Title:           extracted.Title,
+		Content:         extracted.MainText,
+		Description:     extracted.Description,
+		MainText:        extracted.MainText,
+		Headings:        extracted.Headings,
+		Links:           extracted.Links,
+		Images:          extracted.Images,
+		Markdown:        extracted.Markdown,
+		StatusCode:      int(statusCode),
+		Headers:         responseHeaders,
+		MimeType:        mimeType,
 		IsDynamic:       true,
 		LoadingStrategy: "chromedp",
 		LoadTime:        time.Since(startTime),
 	}, nil
 }
 
-// Cleanup closes the browser context
+// Screenshot navigates to targetURL and captures a PNG/JPEG of the page.
+// When opts.FullPage is set it overrides the device metrics to the full
+// scrollable height before capturing, following the same pattern chromedp's
+// own examples and gowitness use for full-page capture.
+func (cm *ChromeDPManager) Screenshot(targetURL string, opts ScreenshotOptions) ([]byte, error) {
+	format := opts.Format
+	if format == "" {
+		format = "png"
+	}
+	width, height := opts.Width, opts.Height
+	if width == 0 {
+		width = 1280
+	}
+	if height == 0 {
+		height = 800
+	}
+
+	ctx, cancel := cm.newTab(30 * time.Second)
+	defer cancel()
+
+	actions := append([]chromedp.Action{
+		chromedp.EmulateViewport(width, height),
+		chromedp.Navigate(targetURL),
+	}, waitPageReady(ctx, opts.WaitSelector, defaultQuietPeriod)...)
+
+	var buf []byte
+	actions = append(actions,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			if !opts.FullPage {
+				return nil
+			}
+			_, _, contentSize, err := page.GetLayoutMetrics().Do(ctx)
+			if err != nil {
+				return fmt.Errorf("get layout metrics: %v", err)
+			}
+			return emulation.SetDeviceMetricsOverride(int64(contentSize.Width), int64(contentSize.Height), 1, false).Do(ctx)
+		}),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			params := page.CaptureScreenshot().WithFormat(page.CaptureScreenshotFormat(format))
+			if format == "jpeg" && opts.Quality > 0 {
+				params = params.WithQuality(int64(opts.Quality))
+			}
+			var err error
+			buf, err = params.Do(ctx)
+			return err
+		}),
+	)
+
+	if err := chromedp.Run(ctx, actions...); err != nil {
+		return nil, fmt.Errorf("chromedp screenshot failed: %v", err)
+	}
+
+	return buf, nil
+}
+
+// ScreenshotElement navigates to targetURL and captures only the element
+// matching selector, scrolling it into view first.
+func (cm *ChromeDPManager) ScreenshotElement(targetURL, selector string) ([]byte, error) {
+	ctx, cancel := cm.newTab(30 * time.Second)
+	defer cancel()
+
+	var buf []byte
+	err := chromedp.Run(ctx,
+		chromedp.Navigate(targetURL),
+		chromedp.WaitVisible(selector, chromedp.ByQuery),
+		chromedp.Screenshot(selector, &buf, chromedp.NodeVisible, chromedp.ByQuery),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("chromedp element screenshot failed: %v", err)
+	}
+
+	return buf, nil
+}
+
+// PDF navigates to targetURL and exports the rendered page as a PDF.
+func (cm *ChromeDPManager) PDF(targetURL string, opts PDFOptions) ([]byte, error) {
+	paperWidth, paperHeight := opts.PaperWidth, opts.PaperHeight
+	if paperWidth == 0 {
+		paperWidth = 8.5
+	}
+	if paperHeight == 0 {
+		paperHeight = 11
+	}
+
+	ctx, cancel := cm.newTab(30 * time.Second)
+	defer cancel()
+
+	actions := append([]chromedp.Action{chromedp.Navigate(targetURL)},
+		waitPageReady(ctx, opts.WaitSelector, defaultQuietPeriod)...)
+
+	var buf []byte
+	actions = append(actions, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		buf, _, err = page.PrintToPDF().
+			WithLandscape(opts.Landscape).
+			WithPrintBackground(opts.PrintBackground).
+			WithPaperWidth(paperWidth).
+			WithPaperHeight(paperHeight).
+			Do(ctx)
+		return err
+	}))
+
+	if err := chromedp.Run(ctx, actions...); err != nil {
+		return nil, fmt.Errorf("chromedp PDF export failed: %v", err)
+	}
+
+	return buf, nil
+}
+
+// Cleanup shuts down the browser this manager launched, or, for a manager
+// created with NewChromeDPManagerRemote, just detaches without touching the
+// remote browser's lifetime.
 func (cm *ChromeDPManager) Cleanup() {
+	if cm.remote {
+		// Leave the allocator context alone: cancelling it would make
+		// chromedp send the remote browser a graceful Browser.Close, which
+		// is exactly what a shared remote Chrome must survive.
+		log.Println("ChromeDPManager: detached from remote Chrome without closing it")
+		return
+	}
 	if cm.cancel != nil {
 		cm.cancel()
 	}
@@ -145,34 +497,379 @@ func (cm *ChromeDPManager) GetCapabilities() map[string]interface{} {
 	}
 }
 
-// isValidContentText filters out CSS, JavaScript, and other non-content text
-func isValidContentTextChrome(text string) bool {
-	if len(text) <= 2 {
-		return false
+// Heading is a single h1-h6 found in the page, in document order.
+type Heading struct {
+	Level int
+	Text  string
+}
+
+// Link is an anchor with both its visible text and target.
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Image is an img tag's source and alt text.
+type Image struct {
+	Src string
+	Alt string
+}
+
+// extractedContent is the result of running a page's HTML through the
+// sanitize + readability pipeline in extractContent.
+type extractedContent struct {
+	Title       string
+	Description string
+	MainText    string
+	Headings    []Heading
+	Links       []Link
+	Images      []Image
+	Markdown    string
+}
+
+var (
+	// mainContentSanitizer allows the structural and formatting tags needed
+	// to render readable article content, stripping everything else
+	// (scripts, styles, inline event handlers, ...). img elements with a src
+	// are kept, so tracking pixels pass through.
+	mainContentSanitizer = bluemonday.NewPolicy().
+		AllowElements(
+			"p", "br", "h1", "h2", "h3", "h4", "h5", "h6",
+			"ul", "ol", "li", "blockquote", "pre", "code", "figcaption",
+			"strong", "b", "em", "i", "a", "img",
+			"article", "section", "main", "div", "span",
+			"table", "thead", "tbody", "tr", "td", "th",
+		).
+		AllowAttrs("href").OnElements("a").
+		AllowAttrs("src", "alt").OnElements("img")
+
+	// descriptionSanitizer strips all markup, used for the plain-text meta
+	// description so it can never carry stray tags into downstream prompts.
+	descriptionSanitizer = bluemonday.StrictPolicy()
+)
+
+// readabilityCandidateTags are the block-level elements the main-content
+// scorer considers when hunting for the page's primary content subtree.
+var readabilityCandidateTags = map[string]bool{
+	"p": true, "div": true, "article": true, "section": true, "main": true, "td": true,
+}
+
+// headingLevels maps an h1-h6 tag name to its numeric level.
+var headingLevels = map[string]int{
+	"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6,
+}
+
+// blockBoundaryTags are elements whose text should never run directly into a
+// sibling's text with no separator: the block tags nodeMarkdown puts a blank
+// line around, plus list, table and line-break elements.
+var blockBoundaryTags = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "main": true,
+	"blockquote": true, "li": true, "ul": true, "ol": true,
+	"table": true, "tr": true, "td": true, "th": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"br": true,
+}
+
+// extractContent runs a readability-style pipeline over raw page HTML:
+// sanitize against an allowlist, parse the result, pick the subtree that
+// findMainContent scores highest as the main content, and collect headings/links/
+// images plus a Markdown rendering alongside it.
+func extractContent(rawHTML string) (*extractedContent, error) {
+	title := firstElementText(rawHTML, "title")
+	description := descriptionSanitizer.Sanitize(metaDescriptionContent(rawHTML))
+
+	sanitized := mainContentSanitizer.Sanitize(rawHTML)
+	doc, err := html.Parse(strings.NewReader(sanitized))
+	if err != nil {
+		return nil, fmt.Errorf("parse sanitized html: %v", err)
+	}
+
+	main := findMainContent(doc)
+
+	return &extractedContent{
+		Title:       title,
+		Description: strings.TrimSpace(description),
+		MainText:    nodeText(main),
+		Headings:    collectHeadings(doc),
+		Links:       collectLinks(doc),
+		Images:      collectImages(doc),
+		Markdown:    nodeMarkdown(main),
+	}, nil
+}
+
+// findMainContent scores every candidate block by text length penalized by
+// link density (the fraction of its text that lives inside <a> tags) and
+// returns the highest-scoring one, falling back to the whole document when
+// nothing scores above the length floor.
+func findMainContent(doc *html.Node) *html.Node {
+	var best *html.Node
+	bestScore := -1.0
+
+	forEachNode(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode || !readabilityCandidateTags[n.Data] {
+			return
+		}
+
+		text := nodeText(n)
+		if len(text) < 50 {
+			return
+		}
+
+		var linkText strings.Builder
+		forEachNode(n, func(c *html.Node) {
+			if c.Type == html.ElementNode && c.Data == "a" {
+				linkText.WriteString(nodeText(c))
+			}
+		})
+
+		density := float64(linkText.Len()) / float64(len(text))
+		score := float64(len(text)) * (1 - density)
+		if score > bestScore {
+			bestScore = score
+			best = n
+		}
+	})
+
+	if best == nil {
+		return doc
+	}
+	return best
+}
+
+// collectHeadings walks the whole document for h1-h6 elements in order.
+func collectHeadings(doc *html.Node) []Heading {
+	var headings []Heading
+	forEachNode(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+		level, ok := headingLevels[n.Data]
+		if !ok {
+			return
+		}
+		if text := nodeText(n); text != "" {
+			headings = append(headings, Heading{Level: level, Text: text})
+		}
+	})
+	return headings
+}
+
+// collectLinks walks the whole document for anchors with both an href and
+// visible text, deduplicating identical (text, href) pairs.
+func collectLinks(doc *html.Node) []Link {
+	var links []Link
+	seen := make(map[string]bool)
+	forEachNode(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode || n.Data != "a" {
+			return
+		}
+		href := nodeAttr(n, "href")
+		text := nodeText(n)
+		if href == "" || text == "" {
+			return
+		}
+		key := text + "|" + href
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		links = append(links, Link{Text: text, URL: href})
+	})
+	return links
+}
+
+// collectImages walks the whole document for img elements that carry a src.
+func collectImages(doc *html.Node) []Image {
+	var images []Image
+	forEachNode(doc, func(n *html.Node) {
+		if n.Type != html.ElementNode || n.Data != "img" {
+			return
+		}
+		src := nodeAttr(n, "src")
+		if src == "" {
+			return
+		}
+		images = append(images, Image{Src: src, Alt: nodeAttr(n, "alt")})
+	})
+	return images
+}
+
+// firstElementText parses rawHTML and returns the text content of the first
+// occurrence of tag, or "" if none is found.
+func firstElementText(rawHTML, tag string) string {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return ""
+	}
+	var result string
+	forEachNode(doc, func(n *html.Node) {
+		if result == "" && n.Type == html.ElementNode && n.Data == tag {
+			result = nodeText(n)
+		}
+	})
+	return result
+}
+
+// metaDescriptionContent parses rawHTML and returns the content attribute of
+// <meta name="description">, or "" if there isn't one.
+func metaDescriptionContent(rawHTML string) string {
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		return ""
 	}
+	var result string
+	forEachNode(doc, func(n *html.Node) {
+		if result != "" || n.Type != html.ElementNode || n.Data != "meta" {
+			return
+		}
+		if nodeAttr(n, "name") == "description" {
+			result = nodeAttr(n, "content")
+		}
+	})
+	return result
+}
 
-	lowerText := strings.ToLower(text)
+// nodeMarkdown renders n's subtree as Markdown, covering the tags the
+// sanitizer allowlist lets through.
+func nodeMarkdown(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
 
-	// Filter out common non-content patterns
-	invalidPatterns := []string{
-		"function(", "var ", "let ", "const ", "return ",
-		"document.", "window.", "console.",
-		"data-", "aria-", "http://", "https://",
-		".css", ".js", ".png", ".jpg",
-		"color:", "background:", "border:",
-		"@media", "@import",
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			sb.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			// handled below
+		default:
+			walkChildren(n, walk)
+			return
+		}
+
+		switch n.Data {
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			sb.WriteString("\n" + strings.Repeat("#", headingLevels[n.Data]) + " ")
+			walkChildren(n, walk)
+			sb.WriteString("\n\n")
+		case "p", "div", "section", "article", "main", "blockquote":
+			walkChildren(n, walk)
+			sb.WriteString("\n\n")
+		case "li":
+			sb.WriteString("- ")
+			walkChildren(n, walk)
+			sb.WriteString("\n")
+		case "strong", "b":
+			sb.WriteString("**")
+			walkChildren(n, walk)
+			sb.WriteString("**")
+		case "em", "i":
+			sb.WriteString("_")
+			walkChildren(n, walk)
+			sb.WriteString("_")
+		case "code":
+			sb.WriteString("`")
+			walkChildren(n, walk)
+			sb.WriteString("`")
+		case "a":
+			href := nodeAttr(n, "href")
+			text := nodeText(n)
+			if href == "" {
+				sb.WriteString(text)
+			} else {
+				sb.WriteString(fmt.Sprintf("[%s](%s)", text, href))
+			}
+		case "img":
+			sb.WriteString(fmt.Sprintf("![%s](%s)", nodeAttr(n, "alt"), nodeAttr(n, "src")))
+		case "br":
+			sb.WriteString("\n")
+		default:
+			walkChildren(n, walk)
+		}
+	}
+	walk(n)
+
+	return strings.TrimSpace(collapseBlankLines(sb.String()))
+}
+
+// walkChildren runs walk over each direct child of n in order.
+func walkChildren(n *html.Node, walk func(*html.Node)) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c)
+	}
+}
+
+// collapseBlankLines drops consecutive blank lines down to at most one, so
+// Markdown output from deeply nested containers doesn't end up full of gaps.
+func collapseBlankLines(s string) string {
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out = append(out, trimmed)
 	}
+	return strings.Join(out, "\n")
+}
+
+// forEachNode calls fn for n and every node in its subtree, pre-order.
+func forEachNode(n *html.Node, fn func(*html.Node)) {
+	fn(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		forEachNode(c, fn)
+	}
+}
 
-	for _, pattern := range invalidPatterns {
-		if strings.Contains(lowerText, pattern) {
-			return false
+// nodeAttr returns the value of n's key attribute, or "" if absent.
+func nodeAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
 		}
 	}
+	return ""
+}
 
-	// Filter out CSS blocks
-	if strings.Contains(text, "{") && strings.Contains(text, "}") {
-		return false
+// nodeText returns n's subtree's text content, collapsed to single spaces.
+// A space is inserted around every blockBoundaryTags element so adjacent
+// blocks with no whitespace-only text node between them in the source (the
+// norm for minified/bundler-rendered HTML) don't have their words glued
+// together.
+func nodeText(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			return
+		}
+		boundary := n.Type == html.ElementNode && blockBoundaryTags[n.Data]
+		if boundary {
+			sb.WriteString(" ")
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if boundary {
+			sb.WriteString(" ")
+		}
 	}
+	walk(n)
 
-	return true
+	return strings.Join(strings.Fields(sb.String()), " ")
 }
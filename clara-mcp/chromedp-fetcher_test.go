@@ -0,0 +1,252 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func parseFragment(t *testing.T, rawHTML string) *html.Node {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(rawHTML))
+	if err != nil {
+		t.Fatalf("html.Parse: %v", err)
+	}
+	return doc
+}
+
+func TestNodeText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "plain text",
+			html: "<p>hello world</p>",
+			want: "hello world",
+		},
+		{
+			// Regression: adjacent block elements with no whitespace between
+			// them in the source (the norm for minified/bundler-rendered
+			// HTML) used to have their words glued together.
+			name: "adjacent blocks without whitespace do not glue words",
+			html: "<div><p>first</p><p>second</p></div>",
+			want: "first second",
+		},
+		{
+			name: "inline elements do not insert a boundary",
+			html: "<p>foo<b>bar</b>baz</p>",
+			want: "foobarbaz",
+		},
+		{
+			name: "nested blocks each get a boundary",
+			html: "<article><h1>Title</h1><div>Body text</div></article>",
+			want: "Title Body text",
+		},
+		{
+			name: "collapses existing whitespace runs",
+			html: "<p>too    many\n\nspaces</p>",
+			want: "too many spaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseFragment(t, tt.html)
+			got := nodeText(doc)
+			if got != tt.want {
+				t.Errorf("nodeText(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "heading levels",
+			html: "<h2>Section</h2>",
+			want: "## Section",
+		},
+		{
+			name: "bold and italic",
+			html: "<p>a <strong>b</strong> <em>c</em></p>",
+			want: "a **b** _c_",
+		},
+		{
+			name: "link with href",
+			html: `<a href="https://example.com">click</a>`,
+			want: "[click](https://example.com)",
+		},
+		{
+			name: "link without href falls back to text",
+			html: "<a>click</a>",
+			want: "click",
+		},
+		{
+			name: "image",
+			html: `<img src="pic.png" alt="a pic">`,
+			want: "![a pic](pic.png)",
+		},
+		{
+			name: "list item gets a dash prefix",
+			html: "<li>item one</li>",
+			want: "- item one",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseFragment(t, tt.html)
+			got := nodeMarkdown(doc)
+			if got != tt.want {
+				t.Errorf("nodeMarkdown(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindMainContent(t *testing.T) {
+	rawHTML := `
+		<html><body>
+			<nav>
+				<a href="/a">link one link one link one</a>
+				<a href="/b">link two link two link two</a>
+			</nav>
+			<article>
+				This article has a lot of real body text that isn't inside any
+				anchor tag at all, so its link density is zero and its score
+				should come out well above the link-heavy nav above it.
+			</article>
+		</body></html>`
+
+	doc := parseFragment(t, rawHTML)
+	main := findMainContent(doc)
+	if main == nil || main.Data != "article" {
+		got := "<nil>"
+		if main != nil {
+			got = main.Data
+		}
+		t.Fatalf("findMainContent picked %q, want \"article\"", got)
+	}
+}
+
+func TestFindMainContentFallsBackToDocument(t *testing.T) {
+	doc := parseFragment(t, "<html><body><p>short</p></body></html>")
+	main := findMainContent(doc)
+	if main != doc {
+		t.Errorf("findMainContent should fall back to the document when nothing clears the length floor")
+	}
+}
+
+func TestCollectLinks(t *testing.T) {
+	rawHTML := `
+		<a href="/one">One</a>
+		<a href="/two">Two</a>
+		<a href="/one">One</a>
+		<a href="/three"></a>
+		<a>No href</a>`
+
+	doc := parseFragment(t, rawHTML)
+	links := collectLinks(doc)
+
+	want := []Link{
+		{Text: "One", URL: "/one"},
+		{Text: "Two", URL: "/two"},
+	}
+	if len(links) != len(want) {
+		t.Fatalf("collectLinks returned %d links, want %d: %+v", len(links), len(want), links)
+	}
+	for i, l := range want {
+		if links[i] != l {
+			t.Errorf("collectLinks[%d] = %+v, want %+v", i, links[i], l)
+		}
+	}
+}
+
+func TestCollectImages(t *testing.T) {
+	rawHTML := `
+		<img src="a.png" alt="A">
+		<img src="b.png">
+		<img alt="no src">`
+
+	doc := parseFragment(t, rawHTML)
+	images := collectImages(doc)
+
+	want := []Image{
+		{Src: "a.png", Alt: "A"},
+		{Src: "b.png", Alt: ""},
+	}
+	if len(images) != len(want) {
+		t.Fatalf("collectImages returned %d images, want %d: %+v", len(images), len(want), images)
+	}
+	for i, img := range want {
+		if images[i] != img {
+			t.Errorf("collectImages[%d] = %+v, want %+v", i, images[i], img)
+		}
+	}
+}
+
+func TestCollectHeadings(t *testing.T) {
+	doc := parseFragment(t, "<h1>Title</h1><p>body</p><h3>Sub</h3>")
+	headings := collectHeadings(doc)
+
+	want := []Heading{
+		{Level: 1, Text: "Title"},
+		{Level: 3, Text: "Sub"},
+	}
+	if len(headings) != len(want) {
+		t.Fatalf("collectHeadings returned %d headings, want %d: %+v", len(headings), len(want), headings)
+	}
+	for i, h := range want {
+		if headings[i] != h {
+			t.Errorf("collectHeadings[%d] = %+v, want %+v", i, headings[i], h)
+		}
+	}
+}
+
+func TestExtractContent(t *testing.T) {
+	rawHTML := `
+		<html>
+		<head>
+			<title>Test Page</title>
+			<meta name="description" content="A page about testing.">
+		</head>
+		<body>
+			<nav><a href="/home">home home home home</a></nav>
+			<article>
+				<h1>Main Heading</h1>
+				<p>This is the main body of the article, long enough to win
+				out over the nav's link-heavy text when scored.</p>
+				<a href="https://example.com">an example link</a>
+			</article>
+		</body>
+		</html>`
+
+	extracted, err := extractContent(rawHTML)
+	if err != nil {
+		t.Fatalf("extractContent: %v", err)
+	}
+
+	if extracted.Title != "Test Page" {
+		t.Errorf("Title = %q, want %q", extracted.Title, "Test Page")
+	}
+	if extracted.Description != "A page about testing." {
+		t.Errorf("Description = %q, want %q", extracted.Description, "A page about testing.")
+	}
+	if !strings.Contains(extracted.MainText, "main body of the article") {
+		t.Errorf("MainText = %q, missing expected article text", extracted.MainText)
+	}
+	if len(extracted.Headings) != 1 || extracted.Headings[0].Text != "Main Heading" {
+		t.Errorf("Headings = %+v, want a single \"Main Heading\" h1", extracted.Headings)
+	}
+	if !strings.Contains(extracted.Markdown, "# Main Heading") {
+		t.Errorf("Markdown = %q, missing rendered heading", extracted.Markdown)
+	}
+}